@@ -0,0 +1,60 @@
+package goparsify
+
+// charRange is an inclusive byte range, e.g. 'a'-'z'.
+type charRange struct {
+	lo, hi byte
+}
+
+func (r charRange) contains(b byte) bool {
+	return b >= r.lo && b <= r.hi
+}
+
+// parseCharset turns a pattern like "a-zA-Z0-9_" into the ranges it
+// describes. A '-' between two bytes denotes a range; any other byte
+// denotes itself.
+func parseCharset(pattern string) []charRange {
+	var ranges []charRange
+	for i := 0; i < len(pattern); i++ {
+		if i+2 < len(pattern) && pattern[i+1] == '-' {
+			ranges = append(ranges, charRange{pattern[i], pattern[i+2]})
+			i += 2
+		} else {
+			ranges = append(ranges, charRange{pattern[i], pattern[i]})
+		}
+	}
+	return ranges
+}
+
+func inCharset(ranges []charRange, b byte) bool {
+	for _, r := range ranges {
+		if r.contains(b) {
+			return true
+		}
+	}
+	return false
+}
+
+// Chars matches one or more consecutive bytes found in ranges, which is
+// parsed the same way as text/ebnf style character ranges, e.g. "a-zA-Z0-9".
+func Chars(ranges string) Parser {
+	set := parseCharset(ranges)
+
+	return func(ps *State, node *Node) {
+		before := ps.Pos
+		ps.WS()
+		start := ps.Pos
+
+		for ps.Pos < len(ps.Input) && inCharset(set, ps.Input[ps.Pos]) {
+			ps.Pos++
+		}
+
+		if ps.Pos == start {
+			ps.ErrorHere(ranges)
+			ps.Pos = before
+			return
+		}
+
+		node.Token = ps.Input[start:ps.Pos]
+		ps.Success()
+	}
+}
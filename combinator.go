@@ -0,0 +1,268 @@
+package goparsify
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Nil matches nothing and always succeeds. It's useful as a default/no-op
+// Parser, and is what And() and Any() fall back to when called with no
+// arguments.
+func Nil(ps *State, node *Node) {
+	ps.Success()
+}
+
+// parserify converts the loosely typed arguments accepted by the
+// combinators below into a Parser. Accepted types are Parser, a plain
+// func(ps *State, node *Node), a string (matched with Exact), and *Parser
+// for forward references to parsers that recurse into themselves.
+func parserify(i interface{}) Parser {
+	switch t := i.(type) {
+	case Parser:
+		return t
+	case func(ps *State, node *Node):
+		return Parser(t)
+	case *Parser:
+		return func(ps *State, node *Node) {
+			(*t)(ps, node)
+		}
+	case string:
+		return Exact(t)
+	default:
+		panic(fmt.Sprintf("goparsify: don't know how to parse a %T, expected a Parser, string, or *Parser", i))
+	}
+}
+
+// Exact matches match literally, with no further interpretation.
+func Exact(match string) Parser {
+	return func(ps *State, node *Node) {
+		start := ps.Pos
+		ps.WS()
+		if strings.HasPrefix(ps.Get(), match) {
+			node.Token = match
+			ps.Pos += len(match)
+			ps.Success()
+			return
+		}
+		ps.ErrorHere(match)
+		ps.Pos = start
+	}
+}
+
+// And requires all given parsers to match in order, and collects their
+// results as Children. If any of them fail, And fails as a whole and rolls
+// back to where it started.
+func And(parsers ...interface{}) Parser {
+	if len(parsers) == 0 {
+		return Nil
+	}
+
+	parserfns := make([]Parser, len(parsers))
+	for i, p := range parsers {
+		parserfns[i] = parserify(p)
+	}
+
+	return func(ps *State, node *Node) {
+		start := ps.Pos
+		children := make([]Node, 0, len(parserfns))
+
+		for _, p := range parserfns {
+			var child Node
+			p(ps, &child)
+			if ps.Errored() {
+				ps.Pos = start
+				return
+			}
+			children = append(children, child)
+		}
+
+		node.Children = children
+	}
+}
+
+// Any tries each parser in turn and returns the result of the first one
+// that matches. If none match, Any fails, and ps.Error holds every
+// expectation recorded at the furthest input offset any alternative reached.
+func Any(parsers ...interface{}) Parser {
+	if len(parsers) == 0 {
+		return Nil
+	}
+
+	parserfns := make([]Parser, len(parsers))
+	for i, p := range parsers {
+		parserfns[i] = parserify(p)
+	}
+
+	return func(ps *State, node *Node) {
+		start := ps.Pos
+
+		for _, p := range parserfns {
+			var child Node
+			p(ps, &child)
+			if !ps.Errored() {
+				*node = child
+				return
+			}
+			ps.Pos = start
+		}
+	}
+}
+
+// Maybe matches parser zero or one times. If parser fails, Maybe still
+// succeeds, having matched nothing.
+func Maybe(parser interface{}) Parser {
+	p := parserify(parser)
+
+	return func(ps *State, node *Node) {
+		start := ps.Pos
+		var child Node
+		p(ps, &child)
+		if ps.Errored() {
+			ps.Pos = start
+			ps.Success()
+			return
+		}
+		*node = child
+	}
+}
+
+// Kleene matches parser zero or more times, optionally separated by sep,
+// and never fails. The matched repetitions (not the separators) become
+// Children. A repetition that matches without advancing ps.Pos (Maybe, or
+// anything else that can succeed on zero-width input) stops the loop after
+// being counted once, rather than repeating forever.
+func Kleene(parser interface{}, sep ...interface{}) Parser {
+	p := parserify(parser)
+	var s Parser
+	if len(sep) > 0 {
+		s = parserify(sep[0])
+	}
+
+	return func(ps *State, node *Node) {
+		var children []Node
+
+		for {
+			repStart := ps.Pos
+			var child Node
+			p(ps, &child)
+			if ps.Errored() {
+				ps.Pos = repStart
+				break
+			}
+			children = append(children, child)
+			if ps.Pos == repStart {
+				break
+			}
+
+			if s != nil {
+				sepStart := ps.Pos
+				var sepNode Node
+				s(ps, &sepNode)
+				if ps.Errored() {
+					ps.Pos = sepStart
+					break
+				}
+			}
+		}
+
+		node.Children = children
+		ps.Success()
+	}
+}
+
+// Many is like Kleene but requires at least one match, failing otherwise.
+func Many(parser interface{}, sep ...interface{}) Parser {
+	p := parserify(parser)
+	var s Parser
+	if len(sep) > 0 {
+		s = parserify(sep[0])
+	}
+
+	return func(ps *State, node *Node) {
+		start := ps.Pos
+		var children []Node
+
+		for {
+			repStart := ps.Pos
+			var child Node
+			p(ps, &child)
+			if ps.Errored() {
+				ps.Pos = repStart
+				break
+			}
+			children = append(children, child)
+			if ps.Pos == repStart {
+				break
+			}
+
+			if s != nil {
+				sepStart := ps.Pos
+				var sepNode Node
+				s(ps, &sepNode)
+				if ps.Errored() {
+					ps.Pos = sepStart
+					break
+				}
+			}
+		}
+
+		if len(children) == 0 {
+			ps.Pos = start
+			return
+		}
+
+		node.Children = children
+		ps.Success()
+	}
+}
+
+// Merge runs parser and flattens everything it matched into a single Token
+// containing the raw input it consumed, discarding the Children structure.
+func Merge(parser interface{}) Parser {
+	p := parserify(parser)
+
+	return func(ps *State, node *Node) {
+		start := ps.Pos
+		var child Node
+		p(ps, &child)
+		if ps.Errored() {
+			return
+		}
+
+		node.Token = ps.Input[start:ps.Pos]
+		node.Span = Span{Pos: start, EndPos: ps.Pos, Tokens: collectTokens(child)}
+	}
+}
+
+// Map runs parser and, on success, passes the resulting Node through f. If
+// f returns a Node whose Result is a struct (or pointer to one) with fields
+// named Pos, EndPos, and/or Tokens, those fields are populated from the
+// parser's matched span via reflection. This gives callers accurate source
+// positions on their AST nodes without having to thread ps.Pos through
+// every mapping callback by hand; MapPos is available for callers who'd
+// rather receive the span explicitly than rely on reflection.
+func Map(parser interface{}, f func(Node) Node) Parser {
+	return MapPos(parser, func(n Node, span Span) Node {
+		return f(n)
+	})
+}
+
+// MapPos is like Map, but f also receives the Span consumed by parser.
+func MapPos(parser interface{}, f func(Node, Span) Node) Parser {
+	p := parserify(parser)
+
+	return func(ps *State, node *Node) {
+		start := ps.Pos
+		var child Node
+		p(ps, &child)
+		if ps.Errored() {
+			return
+		}
+
+		span := Span{Pos: start, EndPos: ps.Pos, Tokens: collectTokens(child)}
+		mapped := f(child, span)
+		mapped.Span = span
+		setSpanFields(&mapped, span)
+		*node = mapped
+	}
+}
@@ -25,8 +25,8 @@ func TestAnd(t *testing.T) {
 
 	t.Run("returns errors", func(t *testing.T) {
 		_, p2 := runParser("hello there", parser)
-		require.Equal(t, "world", p2.Error.Expected)
-		require.Equal(t, 6, p2.Error.pos)
+		require.Equal(t, "world", p2.Error.Expected())
+		require.Equal(t, 6, p2.Error.Pos())
 		require.Equal(t, 0, p2.Pos)
 	})
 
@@ -68,6 +68,18 @@ func TestAny(t *testing.T) {
 		require.Equal(t, 0, p2.Pos)
 	})
 
+	t.Run("Reports every alternative tried at the furthest position", func(t *testing.T) {
+		_, p2 := runParser("hello world?", Any(
+			And("hello", "world", "."),
+			And("hello", "world", "!"),
+		))
+		require.Equal(t, 11, p2.Error.Pos())
+		require.ElementsMatch(t, []Expectation{
+			{Label: ".", Pos: 11},
+			{Label: "!", Pos: 11},
+		}, p2.Error.Expectations)
+	})
+
 	t.Run("Accepts nil matches", func(t *testing.T) {
 		node, p2 := runParser("hello world!", Any(Exact("ffffff")))
 		require.Equal(t, Node{}, node)
@@ -105,6 +117,13 @@ func TestKleene(t *testing.T) {
 		require.Equal(t, 6, p2.Pos)
 		require.Equal(t, "d,e,", p2.Get())
 	})
+
+	t.Run("Stops instead of looping forever on a non-advancing match", func(t *testing.T) {
+		node, p2 := runParser("abc", Kleene(Maybe(Exact("x"))))
+		require.False(t, p2.Errored())
+		require.Len(t, node.Children, 1)
+		require.Equal(t, 0, p2.Pos)
+	})
 }
 
 func TestMany(t *testing.T) {
@@ -132,6 +151,13 @@ func TestMany(t *testing.T) {
 		require.Equal(t, "offset 0: Expected def", p2.Error.Error())
 		require.Equal(t, "a,b,c,d,e,", p2.Get())
 	})
+
+	t.Run("Stops instead of looping forever on a non-advancing match", func(t *testing.T) {
+		node, p2 := runParser("abc", Many(Maybe(Exact("x"))))
+		require.False(t, p2.Errored())
+		require.Len(t, node.Children, 1)
+		require.Equal(t, 0, p2.Pos)
+	})
 }
 
 type htmlTag struct {
@@ -155,6 +181,83 @@ func TestMap(t *testing.T) {
 	})
 }
 
+type spannedTag struct {
+	Name   string
+	Pos    int
+	EndPos int
+	Tokens []string
+}
+
+type tokenList []string
+
+type spannedTagWithInt64 struct {
+	Name   string
+	Pos    int64
+	EndPos int64
+	Tokens tokenList
+}
+
+func TestMapPos(t *testing.T) {
+	parser := Map(And("<", Chars("a-zA-Z0-9"), ">"), func(n Node) Node {
+		return Node{Result: spannedTag{Name: n.Children[1].Token}}
+	})
+
+	t.Run("populates Pos/EndPos/Tokens via reflection", func(t *testing.T) {
+		result, _ := runParser("<html>", parser)
+		tag := result.Result.(spannedTag)
+		require.Equal(t, "html", tag.Name)
+		require.Equal(t, 0, tag.Pos)
+		require.Equal(t, 6, tag.EndPos)
+		require.Equal(t, []string{"<", "html", ">"}, tag.Tokens)
+	})
+
+	t.Run("populates integer kinds other than int, and named slice types", func(t *testing.T) {
+		typed := Map(And("<", Chars("a-zA-Z0-9"), ">"), func(n Node) Node {
+			return Node{Result: spannedTagWithInt64{Name: n.Children[1].Token}}
+		})
+		result, _ := runParser("<html>", typed)
+		tag := result.Result.(spannedTagWithInt64)
+		require.Equal(t, "html", tag.Name)
+		require.Equal(t, int64(0), tag.Pos)
+		require.Equal(t, int64(6), tag.EndPos)
+		require.Equal(t, tokenList{"<", "html", ">"}, tag.Tokens)
+	})
+
+	t.Run("collects Tokens through nested Map'd children", func(t *testing.T) {
+		word := Map(Chars("a-z"), func(n Node) Node {
+			return Node{Result: n.Token}
+		})
+		outer := Map(Many(word), func(n Node) Node {
+			return Node{Result: spannedTag{}}
+		})
+
+		result, _ := runParser("ab cd ef", outer)
+		tag := result.Result.(spannedTag)
+		require.Equal(t, 0, tag.Pos)
+		require.Equal(t, 8, tag.EndPos)
+		require.Equal(t, []string{"ab", "cd", "ef"}, tag.Tokens)
+	})
+
+	t.Run("leaves unrelated structs untouched", func(t *testing.T) {
+		plain := Map(And("<", Chars("a-zA-Z0-9"), ">"), func(n Node) Node {
+			return Node{Result: htmlTag{n.Children[1].Token}}
+		})
+		result, _ := runParser("<html>", plain)
+		require.Equal(t, htmlTag{"html"}, result.Result)
+	})
+
+	t.Run("MapPos receives the span explicitly", func(t *testing.T) {
+		var gotSpan Span
+		explicit := MapPos(And("<", Chars("a-zA-Z0-9"), ">"), func(n Node, span Span) Node {
+			gotSpan = span
+			return Node{Result: n.Children[1].Token}
+		})
+		result, _ := runParser("<html>", explicit)
+		require.Equal(t, "html", result.Result)
+		require.Equal(t, Span{Pos: 0, EndPos: 6, Tokens: []string{"<", "html", ">"}}, gotSpan)
+	})
+}
+
 func TestMerge(t *testing.T) {
 	var bracer Parser
 	bracer = And("(", Maybe(&bracer), ")")
@@ -0,0 +1,214 @@
+// Package ebnf compiles a declarative EBNF grammar into goparsify parsers,
+// so that a grammar can be prototyped without writing combinator calls by
+// hand.
+//
+// The grammar syntax mirrors Go's text/ebnf: productions are written
+// `Name = Expression .`, with `|` for alternation, `()` for grouping, `[]`
+// for an optional sub-expression and `{}` for zero-or-more repetition.
+// Terminals are either quoted strings, e.g. "foo", which compile to
+// goparsify.Exact, or character ranges in angle brackets, e.g. <a-zA-Z0-9>,
+// which compile to goparsify.Chars. For example:
+//
+//	bracer = "(" [ bracer ] ")" .
+package ebnf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tedkornish/goparsify"
+)
+
+// stringLiteral matches a "..." or '...' quoted terminal.
+func stringLiteral(ps *goparsify.State, node *goparsify.Node) {
+	start := ps.Pos
+	ps.WS()
+	rest := ps.Get()
+	if len(rest) == 0 || (rest[0] != '"' && rest[0] != '\'') {
+		ps.ErrorHere("string literal")
+		ps.Pos = start
+		return
+	}
+
+	quote := rest[0]
+	end := strings.IndexByte(rest[1:], quote)
+	if end < 0 {
+		ps.ErrorHere("closing " + string(quote))
+		ps.Pos = start
+		return
+	}
+
+	node.Token = rest[1 : end+1]
+	ps.Pos += end + 2
+	ps.Success()
+}
+
+// charRangeLiteral matches a <...> character-range terminal.
+func charRangeLiteral(ps *goparsify.State, node *goparsify.Node) {
+	start := ps.Pos
+	ps.WS()
+	rest := ps.Get()
+	if len(rest) == 0 || rest[0] != '<' {
+		ps.ErrorHere("char range")
+		ps.Pos = start
+		return
+	}
+
+	end := strings.IndexByte(rest[1:], '>')
+	if end < 0 {
+		ps.ErrorHere("closing >")
+		ps.Pos = start
+		return
+	}
+
+	node.Token = rest[1 : end+1]
+	ps.Pos += end + 2
+	ps.Success()
+}
+
+var productionName = goparsify.Chars("a-zA-Z0-9_")
+
+// expression is forward-declared so that factor can reference it by address
+// for groups, options and repetitions, which all nest an Expression. It's
+// assigned for real in the init below, the same way a recursive production
+// is built up in goparsify itself (see And/Maybe's use of *Parser).
+var expression goparsify.Parser
+
+var factor = goparsify.Any(
+	goparsify.Map(stringLiteral, func(n goparsify.Node) goparsify.Node {
+		return goparsify.Node{Result: term(literal(n.Token))}
+	}),
+	goparsify.Map(charRangeLiteral, func(n goparsify.Node) goparsify.Node {
+		return goparsify.Node{Result: term(charRange(n.Token))}
+	}),
+	goparsify.Map(goparsify.And("(", &expression, ")"), func(n goparsify.Node) goparsify.Node {
+		return goparsify.Node{Result: term(group{n.Children[1].Result.(term)})}
+	}),
+	goparsify.Map(goparsify.And("[", &expression, "]"), func(n goparsify.Node) goparsify.Node {
+		return goparsify.Node{Result: term(option{n.Children[1].Result.(term)})}
+	}),
+	goparsify.Map(goparsify.And("{", &expression, "}"), func(n goparsify.Node) goparsify.Node {
+		return goparsify.Node{Result: term(repetition{n.Children[1].Result.(term)})}
+	}),
+	goparsify.Map(productionName, func(n goparsify.Node) goparsify.Node {
+		return goparsify.Node{Result: term(ref(n.Token))}
+	}),
+)
+
+var concatenation = goparsify.Map(goparsify.Many(factor), func(n goparsify.Node) goparsify.Node {
+	return goparsify.Node{Result: factorsToTerm(n.Children)}
+})
+
+func init() {
+	expression = goparsify.Map(goparsify.Many(concatenation, "|"), func(n goparsify.Node) goparsify.Node {
+		if len(n.Children) == 1 {
+			return goparsify.Node{Result: n.Children[0].Result}
+		}
+		terms := make([]term, len(n.Children))
+		for i, c := range n.Children {
+			terms[i] = c.Result.(term)
+		}
+		return goparsify.Node{Result: term(alt(terms))}
+	})
+}
+
+// factorsToTerm collapses a run of concatenated factors into a single term,
+// skipping the seq wrapper when there's only one factor.
+func factorsToTerm(children []goparsify.Node) term {
+	if len(children) == 1 {
+		return children[0].Result.(term)
+	}
+	terms := make([]term, len(children))
+	for i, c := range children {
+		terms[i] = c.Result.(term)
+	}
+	return seq(terms)
+}
+
+type production struct {
+	name string
+	expr term
+}
+
+var productionParser = goparsify.Map(goparsify.And(productionName, "=", &expression, "."), func(n goparsify.Node) goparsify.Node {
+	return goparsify.Node{Result: production{
+		name: n.Children[0].Token,
+		expr: n.Children[2].Result.(term),
+	}}
+})
+
+var grammarParser = goparsify.Many(productionParser)
+
+// symbolTable holds a placeholder *goparsify.Parser for every production in
+// a grammar, declared before any of them are built, so that productions can
+// refer to each other (forward or recursively) by name.
+type symbolTable struct {
+	placeholders map[string]*goparsify.Parser
+	missing      map[string]bool
+}
+
+func newSymbolTable(names []string) *symbolTable {
+	sym := &symbolTable{
+		placeholders: make(map[string]*goparsify.Parser, len(names)),
+		missing:      map[string]bool{},
+	}
+	for _, name := range names {
+		sym.placeholders[name] = new(goparsify.Parser)
+	}
+	return sym
+}
+
+func (sym *symbolTable) placeholder(name string) *goparsify.Parser {
+	if ptr, ok := sym.placeholders[name]; ok {
+		return ptr
+	}
+	sym.missing[name] = true
+	ptr := new(goparsify.Parser)
+	*ptr = goparsify.Nil
+	sym.placeholders[name] = ptr
+	return ptr
+}
+
+// Parse compiles an EBNF grammar into a goparsify.Parser for every
+// production it defines, plus the root parser for the grammar as a whole,
+// which by convention is whichever production is defined first.
+func Parse(grammar string) (map[string]goparsify.Parser, goparsify.Parser, error) {
+	ps := goparsify.NewState(grammar)
+	result := goparsify.Node{}
+	grammarParser(ps, &result)
+	if ps.Errored() {
+		return nil, nil, &ps.Error
+	}
+	if ps.Pos != len(grammar) && strings.TrimSpace(ps.Get()) != "" {
+		return nil, nil, fmt.Errorf("ebnf: unexpected input at offset %d: %s", ps.Pos, strconv.Quote(ps.Preview(20)))
+	}
+
+	defs := make([]production, len(result.Children))
+	for i, c := range result.Children {
+		defs[i] = c.Result.(production)
+	}
+
+	names := make([]string, len(defs))
+	for i, d := range defs {
+		names[i] = d.name
+	}
+	sym := newSymbolTable(names)
+
+	parsers := make(map[string]goparsify.Parser, len(defs))
+	for _, d := range defs {
+		p := d.expr.build(sym)
+		*sym.placeholders[d.name] = p
+		parsers[d.name] = p
+	}
+
+	if len(sym.missing) > 0 {
+		undefined := make([]string, 0, len(sym.missing))
+		for name := range sym.missing {
+			undefined = append(undefined, name)
+		}
+		return nil, nil, fmt.Errorf("ebnf: undefined production(s): %s", strings.Join(undefined, ", "))
+	}
+
+	return parsers, parsers[defs[0].name], nil
+}
@@ -0,0 +1,74 @@
+package ebnf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tedkornish/goparsify"
+)
+
+func parse(t *testing.T, grammar string) (map[string]goparsify.Parser, goparsify.Parser) {
+	t.Helper()
+	parsers, root, err := Parse(grammar)
+	require.NoError(t, err)
+	return parsers, root
+}
+
+func run(parser goparsify.Parser, input string) (goparsify.Node, *goparsify.State) {
+	ps := goparsify.NewState(input)
+	node := goparsify.Node{}
+	parser(ps, &node)
+	return node, ps
+}
+
+func TestParseRecursiveGrammar(t *testing.T) {
+	_, bracer := parse(t, `bracer = "(" [ bracer ] ")" .`)
+
+	t.Run("matches nested brackets", func(t *testing.T) {
+		_, ps := run(bracer, "((()))")
+		require.False(t, ps.Errored())
+		require.Equal(t, 6, ps.Pos)
+		require.Equal(t, "", ps.Get())
+	})
+
+	t.Run("fails on unbalanced input", func(t *testing.T) {
+		_, ps := run(bracer, "((())")
+		require.True(t, ps.Errored())
+		require.Equal(t, 0, ps.Pos)
+	})
+}
+
+func TestParseAlternationAndCharRanges(t *testing.T) {
+	parsers, root := parse(t, `
+		greeting = ( "hello" | "hi" ) name .
+		name     = <a-zA-Z> .
+	`)
+	require.Contains(t, parsers, "greeting")
+	require.Contains(t, parsers, "name")
+
+	node, ps := run(root, "hi Bob")
+	require.False(t, ps.Errored())
+	require.Equal(t, 6, ps.Pos)
+	require.Equal(t, "hi", node.Children[0].Token)
+	require.Equal(t, "Bob", node.Children[1].Token)
+}
+
+func TestParseRepetitionOverNullableSubExpression(t *testing.T) {
+	_, root := parse(t, `run = { [ "a" ] "," } .`)
+
+	node, ps := run(root, "a,a,,")
+	require.False(t, ps.Errored())
+	require.Equal(t, 5, ps.Pos)
+	require.Len(t, node.Children, 3)
+}
+
+func TestParseUndefinedProduction(t *testing.T) {
+	_, _, err := Parse(`broken = missing .`)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "missing")
+}
+
+func TestParseSyntaxError(t *testing.T) {
+	_, _, err := Parse(`broken = "x"`)
+	require.Error(t, err)
+}
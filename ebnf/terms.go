@@ -0,0 +1,81 @@
+package ebnf
+
+import "github.com/tedkornish/goparsify"
+
+// term is a single node in the AST produced by parsing an EBNF grammar. Each
+// implementation knows how to compile itself down to a goparsify.Parser once
+// every production in the grammar has a symbol table entry.
+type term interface {
+	build(sym *symbolTable) goparsify.Parser
+}
+
+// literal is a quoted terminal, e.g. "foo", matched verbatim.
+type literal string
+
+func (t literal) build(sym *symbolTable) goparsify.Parser {
+	return goparsify.Exact(string(t))
+}
+
+// charRange is a character-range terminal, e.g. <a-zA-Z0-9>, matched the
+// same way goparsify.Chars matches its ranges argument.
+type charRange string
+
+func (t charRange) build(sym *symbolTable) goparsify.Parser {
+	return goparsify.Chars(string(t))
+}
+
+// ref is a reference to another production by name.
+type ref string
+
+func (t ref) build(sym *symbolTable) goparsify.Parser {
+	ptr := sym.placeholder(string(t))
+	return func(ps *goparsify.State, node *goparsify.Node) {
+		(*ptr)(ps, node)
+	}
+}
+
+// group is a parenthesized sub-expression. It exists as a distinct AST node
+// for clarity, but grouping has already done its job by the time we parsed
+// the grammar, so it compiles to exactly what its inner term compiles to.
+type group struct{ inner term }
+
+func (t group) build(sym *symbolTable) goparsify.Parser {
+	return t.inner.build(sym)
+}
+
+// option is a bracketed `[ Expression ]`, matched zero or one times.
+type option struct{ inner term }
+
+func (t option) build(sym *symbolTable) goparsify.Parser {
+	return goparsify.Maybe(t.inner.build(sym))
+}
+
+// repetition is a braced `{ Expression }`, matched zero or more times.
+type repetition struct{ inner term }
+
+func (t repetition) build(sym *symbolTable) goparsify.Parser {
+	return goparsify.Kleene(t.inner.build(sym))
+}
+
+// seq is concatenation: a run of factors that must all match in order.
+type seq []term
+
+func (t seq) build(sym *symbolTable) goparsify.Parser {
+	parsers := make([]interface{}, len(t))
+	for i, term := range t {
+		parsers[i] = term.build(sym)
+	}
+	return goparsify.And(parsers...)
+}
+
+// alt is alternation: a set of terms separated by `|`, tried in order; the
+// first one that matches is used.
+type alt []term
+
+func (t alt) build(sym *symbolTable) goparsify.Parser {
+	parsers := make([]interface{}, len(t))
+	for i, term := range t {
+		parsers[i] = term.build(sym)
+	}
+	return goparsify.Any(parsers...)
+}
@@ -0,0 +1,46 @@
+package goparsify
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Expectation records that a parser was looking for Label at input offset
+// Pos. State.ErrorHere keeps one per distinct label expected at the furthest
+// offset a parse reached, so a failure can report everything that was on the
+// table there, not just whichever alternative happened to get furthest itself.
+type Expectation struct {
+	Label string
+	Pos   int
+}
+
+// Error collects every Expectation recorded at the furthest input offset a
+// parse reached before failing there. Backtracking can undo ps.Pos, but the
+// Expectations recorded here are never un-recorded, so the error a caller
+// sees is always the most useful one a parse came across.
+type Error struct {
+	Expectations []Expectation
+}
+
+// Pos returns the furthest input offset any Expectation was recorded at, or
+// zero if none have been.
+func (e *Error) Pos() int {
+	if len(e.Expectations) == 0 {
+		return 0
+	}
+	return e.Expectations[0].Pos
+}
+
+// Expected returns every label expected at Pos, joined for display.
+func (e *Error) Expected() string {
+	labels := make([]string, len(e.Expectations))
+	for i, exp := range e.Expectations {
+		labels[i] = exp.Label
+	}
+	return strings.Join(labels, ", ")
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("offset %d: Expected %s", e.Pos(), e.Expected())
+}
@@ -0,0 +1,70 @@
+package goparsify
+
+import "sync/atomic"
+
+// memoEntry is the cached outcome of running a Memoize-wrapped parser at a
+// particular input offset.
+type memoEntry struct {
+	node    Node
+	endPos  int
+	errored bool
+	err     Error
+}
+
+// nextParserID assigns each Memoize-wrapped parser a distinct id, used as
+// the outer key of State.memo so that different memoized parsers don't
+// collide with each other at the same input offset. It's incremented
+// atomically since Memoize can legitimately be called while building
+// grammars concurrently, e.g. from multiple goroutines' init-time setup.
+var nextParserID int64
+
+func newParserID() int {
+	return int(atomic.AddInt64(&nextParserID, 1))
+}
+
+// Memoize wraps p so that repeated attempts to match it at the same input
+// offset replay the cached result instead of re-running p. This turns
+// exponential-time ambiguous grammars -- the common case with Any over
+// overlapping alternatives -- into linear time, since every (parser,
+// offset) pair is only ever actually parsed once. It's also a prerequisite
+// for supporting direct left recursion via the seed-parse/grow-the-seed
+// technique, since that needs a place to store and replay a growing
+// provisional result for a parser that calls itself at the position it
+// started from.
+func Memoize(p interface{}) Parser {
+	parser := parserify(p)
+	id := newParserID()
+
+	return func(ps *State, node *Node) {
+		if ps.memo == nil {
+			ps.memo = map[int]map[int]memoEntry{}
+		}
+		byPos, ok := ps.memo[id]
+		if !ok {
+			byPos = map[int]memoEntry{}
+			ps.memo[id] = byPos
+		}
+
+		if entry, ok := byPos[ps.Pos]; ok {
+			if entry.errored {
+				for _, exp := range entry.err.Expectations {
+					ps.mergeExpectation(exp)
+				}
+				return
+			}
+			*node = entry.node
+			ps.Pos = entry.endPos
+			ps.Success()
+			return
+		}
+
+		start := ps.Pos
+		parser(ps, node)
+
+		if ps.Errored() {
+			byPos[start] = memoEntry{errored: true, err: ps.Error}
+			return
+		}
+		byPos[start] = memoEntry{node: *node, endPos: ps.Pos}
+	}
+}
@@ -0,0 +1,113 @@
+package goparsify
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoize(t *testing.T) {
+	t.Run("caches a successful match at a given offset", func(t *testing.T) {
+		calls := 0
+		counting := Parser(func(ps *State, node *Node) {
+			calls++
+			Chars("a-z")(ps, node)
+		})
+		memoized := Memoize(counting)
+
+		ps := NewState("hello world")
+		var first, second Node
+		memoized(ps, &first)
+		require.False(t, ps.Errored())
+		require.Equal(t, 5, ps.Pos)
+
+		ps.Pos = 0
+		memoized(ps, &second)
+
+		require.Equal(t, 1, calls)
+		require.Equal(t, first, second)
+		require.Equal(t, 5, ps.Pos)
+	})
+
+	t.Run("caches a failure at a given offset", func(t *testing.T) {
+		calls := 0
+		counting := Parser(func(ps *State, node *Node) {
+			calls++
+			Exact("nope")(ps, node)
+		})
+		memoized := Memoize(counting)
+
+		ps := NewState("hello")
+		var node Node
+		memoized(ps, &node)
+		require.True(t, ps.Errored())
+		firstErr := ps.Error
+
+		memoized(ps, &node)
+
+		require.Equal(t, 1, calls)
+		require.True(t, ps.Errored())
+		require.Equal(t, firstErr, ps.Error)
+	})
+
+	t.Run("different offsets are tracked independently", func(t *testing.T) {
+		memoized := Memoize(Chars("a-z"))
+		node, ps := runParser("ab cd", And(memoized, memoized))
+		require.False(t, ps.Errored())
+		assertSequence(t, node, "ab", "cd")
+	})
+
+	t.Run("assigns distinct ids under concurrent construction", func(t *testing.T) {
+		const n = 200
+		ids := make([]int, n)
+
+		var wg sync.WaitGroup
+		wg.Add(n)
+		for i := 0; i < n; i++ {
+			i := i
+			go func() {
+				defer wg.Done()
+				ids[i] = newParserID()
+			}()
+		}
+		wg.Wait()
+
+		seen := make(map[int]bool, n)
+		for _, id := range ids {
+			require.False(t, seen[id], "parser id %d assigned more than once", id)
+			seen[id] = true
+		}
+	})
+}
+
+// buildAmbiguousDigits builds a deeply ambiguous grammar of the classic
+// shape that makes backtracking parsers blow up exponentially: at every
+// level, matching either one "layer" of digits or two in a row, both of
+// which bottom out in the same shared sub-parser, so without memoization
+// the number of times that sub-parser is invoked doubles with each level.
+func buildAmbiguousDigits(depth int, memoize bool) Parser {
+	p := Chars("0-9")
+	for i := 0; i < depth; i++ {
+		inner := p
+		if memoize {
+			inner = Memoize(inner)
+		}
+		p = Any(And(inner, inner), inner)
+	}
+	return p
+}
+
+func BenchmarkAmbiguousGrammarUnmemoized(b *testing.B) {
+	parser := buildAmbiguousDigits(12, false)
+	for i := 0; i < b.N; i++ {
+		runParser("1234567890", parser)
+	}
+}
+
+func BenchmarkAmbiguousGrammarMemoized(b *testing.B) {
+	parser := buildAmbiguousDigits(12, true)
+	for i := 0; i < b.N; i++ {
+		runParser("1234567890", parser)
+	}
+}
@@ -0,0 +1,135 @@
+package goparsify
+
+// Node is the result of a parse. Leaf parsers (Exact, Chars, ...) fill in
+// Token, sequence combinators (And, Kleene, Many) fill in Children, Any
+// passes on the Node of whichever alternative matched, and Map/MapPos fill
+// in Result with whatever value the caller's mapping function produced.
+type Node struct {
+	Token    string
+	Result   interface{}
+	Children []Node
+	Span     Span
+}
+
+// Parser consumes as much of ps as it can starting at ps.Pos. On success it
+// fills in node and leaves ps.Pos just past the match. On failure it must
+// leave ps.Pos unchanged and record what it was looking for via
+// ps.ErrorHere, so that callers composing parsers can always roll back to
+// where they started.
+type Parser func(ps *State, node *Node)
+
+// State tracks where a parse has got to in the input, plus the furthest
+// failure seen so far so that a useful error can be reported even after
+// backtracking.
+type State struct {
+	Input string
+	Pos   int
+	Error Error
+
+	// Tracer, if set, receives Enter/Exit notifications for every parser
+	// wrapped in Trace during this parse. It overrides the tracer installed
+	// with SetTracer for the duration of this parse only.
+	Tracer Tracer
+
+	errored bool
+
+	// memo caches the outcome of every Memoize-wrapped parser by
+	// (parser id, input offset). It's allocated lazily on first use so
+	// parses that don't use Memoize pay nothing for it.
+	memo map[int]map[int]memoEntry
+
+	// recovered holds every Error that Recover swallowed during this parse,
+	// in the order they were encountered.
+	recovered []Error
+}
+
+// NewState creates a new parser state around an input string.
+func NewState(input string) *State {
+	return &State{Input: input}
+}
+
+// Errored reports whether the most recently attempted parse failed. It
+// resets on every successful match, so it reflects the outcome of the last
+// thing that ran, not whether any error has ever been seen.
+func (ps *State) Errored() bool {
+	return ps.errored
+}
+
+// ErrorHere records that a parser failed at the current position while
+// looking for expected. Only the Expectations recorded at the furthest
+// position reached so far are kept: a failure further along discards
+// everything recorded before it, a failure at the same position joins it,
+// and a failure short of it is ignored. That way, after a bunch of
+// backtracking, ps.Error reports every alternative that was tried at the
+// point parsing actually got stuck, not just whichever got there first.
+func (ps *State) ErrorHere(expected string) {
+	ps.mergeExpectation(Expectation{Label: expected, Pos: ps.Pos})
+}
+
+// mergeExpectation folds exp into ps.Error under the same furthest-position
+// ratchet ErrorHere uses. It's also how Memoize replays a cached failure's
+// Expectations without having to re-run the parser that produced them.
+func (ps *State) mergeExpectation(exp Expectation) {
+	switch {
+	case len(ps.Error.Expectations) == 0 || exp.Pos > ps.Error.Pos():
+		ps.Error.Expectations = []Expectation{exp}
+	case exp.Pos == ps.Error.Pos():
+		for _, e := range ps.Error.Expectations {
+			if e.Label == exp.Label {
+				ps.errored = true
+				return
+			}
+		}
+		ps.Error.Expectations = append(ps.Error.Expectations, exp)
+	}
+	ps.errored = true
+}
+
+// Errors returns every Error that Recover swallowed and recorded during this
+// parse, in the order they were encountered, so that a caller can report
+// every problem found in the input instead of just the first one.
+func (ps *State) Errors() []Error {
+	return ps.recovered
+}
+
+// Success clears the errored flag after a parser matches, without touching
+// the furthest-error record.
+func (ps *State) Success() {
+	ps.errored = false
+}
+
+// Get returns the remainder of the input that has not yet been consumed.
+func (ps *State) Get() string {
+	return ps.Input[ps.Pos:]
+}
+
+// Preview returns up to n bytes of unconsumed input, for use in debug output.
+func (ps *State) Preview(n int) string {
+	rest := ps.Get()
+	if len(rest) > n {
+		rest = rest[:n]
+	}
+	return rest
+}
+
+// WS advances past any run of leading whitespace. Leaf parsers call this
+// before attempting to match, which is what gives goparsify its automatic
+// whitespace skipping between tokens.
+func (ps *State) WS() {
+	for ps.Pos < len(ps.Input) && isSpace(ps.Input[ps.Pos]) {
+		ps.Pos++
+	}
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
+
+// runParser runs parser against input from the start and returns the resulting
+// node along with the final state. It exists for use by the test suite.
+func runParser(input string, parser interface{}) (Node, *State) {
+	ps := NewState(input)
+	node := Node{}
+	parserify(parser)(ps, &node)
+	return node, ps
+}
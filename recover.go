@@ -0,0 +1,40 @@
+package goparsify
+
+// Recover runs inner; if inner matches, Recover just returns its result. If
+// inner fails, Recover records the failure (retrievable afterwards via
+// State.Errors) instead of letting it abort the parse, then skips input one
+// byte at a time until sync matches (without consuming sync itself) or the
+// input runs out. The skipped span is returned as a Node whose Result holds
+// the Error that was recorded, so that a caller composing parsers with And
+// or Many can keep going past a bad token and report every problem it finds
+// in one pass, IDE-style, instead of stopping at the first one.
+func Recover(sync interface{}, inner interface{}) Parser {
+	syncParser := parserify(sync)
+	innerParser := parserify(inner)
+
+	return func(ps *State, node *Node) {
+		start := ps.Pos
+		innerParser(ps, node)
+		if !ps.Errored() {
+			return
+		}
+
+		failure := ps.Error
+		ps.recovered = append(ps.recovered, failure)
+		ps.Pos = start
+
+		for ps.Pos < len(ps.Input) {
+			checkpoint := ps.Pos
+			var syncNode Node
+			syncParser(ps, &syncNode)
+			if !ps.Errored() {
+				ps.Pos = checkpoint
+				break
+			}
+			ps.Pos++
+		}
+
+		*node = Node{Token: ps.Input[start:ps.Pos], Result: failure}
+		ps.Success()
+	}
+}
@@ -0,0 +1,41 @@
+package goparsify
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecover(t *testing.T) {
+	statement := Chars("0-9")
+
+	t.Run("passes through when inner matches", func(t *testing.T) {
+		node, ps := runParser("1", Recover(Exact(";"), statement))
+		require.Equal(t, "1", node.Token)
+		require.False(t, ps.Errored())
+		require.Empty(t, ps.Errors())
+	})
+
+	t.Run("skips to sync and records the failure on a bad token", func(t *testing.T) {
+		node, ps := runParser("bad;3", Recover(Exact(";"), statement))
+
+		require.False(t, ps.Errored())
+		require.Equal(t, "bad", node.Token)
+		require.Equal(t, 3, ps.Pos)
+
+		errs := ps.Errors()
+		require.Len(t, errs, 1)
+		require.Equal(t, "0-9", errs[0].Expected())
+		require.Equal(t, node.Result, errs[0])
+	})
+
+	t.Run("lets Many keep going past a bad token and report every error", func(t *testing.T) {
+		parser := Many(Recover(Exact(";"), statement), ";")
+		node, ps := runParser("1;bad;3", parser)
+
+		require.False(t, ps.Errored())
+		require.Equal(t, 7, ps.Pos)
+		assertSequence(t, node, "1", "bad", "3")
+		require.Len(t, ps.Errors(), 1)
+	})
+}
@@ -0,0 +1,93 @@
+package goparsify
+
+import "reflect"
+
+// Span describes the region of input a parser matched: its start and end
+// offsets, and the raw tokens of everything it consumed. Map and MapPos
+// compute a Span for every successful match so that mapping callbacks can
+// build ASTs with accurate source positions, suitable for downstream error
+// reporting, without manually threading offsets through every callback.
+type Span struct {
+	Pos    int
+	EndPos int
+	Tokens []string
+}
+
+// collectTokens flattens a Node's Children into the list of raw tokens they
+// matched, in order. A child with no Children of its own falls back to its
+// own Span.Tokens before its Token, since a node produced by Map/MapPos
+// usually has neither Children nor Token set -- its matched text only
+// survives in the Span that MapPos already computed for it.
+func collectTokens(node Node) []string {
+	if len(node.Children) == 0 {
+		if len(node.Span.Tokens) > 0 {
+			return node.Span.Tokens
+		}
+		if node.Token == "" {
+			return nil
+		}
+		return []string{node.Token}
+	}
+
+	var tokens []string
+	for _, child := range node.Children {
+		tokens = append(tokens, collectTokens(child)...)
+	}
+	return tokens
+}
+
+// setSpanFields uses reflection to populate fields named Pos, EndPos, and
+// Tokens on node.Result, if it is a struct (or pointer to one) that has
+// them. Pos and EndPos may be any signed integer kind (int, int32, int64,
+// ...) and Tokens may be []string or any type convertible to it. Fields
+// that don't exist, aren't settable, or have an incompatible type are left
+// untouched.
+func setSpanFields(node *Node, span Span) {
+	if node.Result == nil {
+		return
+	}
+
+	orig := reflect.ValueOf(node.Result)
+	isPtr := orig.Kind() == reflect.Ptr
+
+	var target reflect.Value
+	if isPtr {
+		if orig.IsNil() {
+			return
+		}
+		target = orig.Elem()
+	} else {
+		target = reflect.New(orig.Type()).Elem()
+		target.Set(orig)
+	}
+
+	if target.Kind() != reflect.Struct {
+		return
+	}
+
+	changed := false
+	if f := target.FieldByName("Pos"); f.IsValid() && f.CanSet() && isIntKind(f.Kind()) {
+		f.SetInt(int64(span.Pos))
+		changed = true
+	}
+	if f := target.FieldByName("EndPos"); f.IsValid() && f.CanSet() && isIntKind(f.Kind()) {
+		f.SetInt(int64(span.EndPos))
+		changed = true
+	}
+	tokensType := reflect.TypeOf(span.Tokens)
+	if f := target.FieldByName("Tokens"); f.IsValid() && f.CanSet() && tokensType.ConvertibleTo(f.Type()) {
+		f.Set(reflect.ValueOf(span.Tokens).Convert(f.Type()))
+		changed = true
+	}
+
+	if changed && !isPtr {
+		node.Result = target.Interface()
+	}
+}
+
+// isIntKind reports whether k is one of Go's signed integer kinds, so that
+// setSpanFields can populate Pos/EndPos fields declared as int, int32,
+// int64, and so on, not just a literal int.
+func isIntKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
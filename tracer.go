@@ -0,0 +1,101 @@
+package goparsify
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+)
+
+// Tracer receives notifications as parsers wrapped in Trace are entered and
+// exited. Unlike the -tags debug instrumentation in debugon.go, it needs no
+// special build and can be attached to a single parse via State.Tracer, or
+// to every parse via SetTracer, so tracing can be turned on for a single
+// request in production without recompiling.
+type Tracer interface {
+	// Enter is called just before the named parser attempts to match.
+	Enter(name string, ps *State)
+	// Exit is called just after the named parser has finished. result is
+	// nil if it failed, in which case err describes why.
+	Exit(name string, ps *State, result *Node, err *Error)
+}
+
+// tracerBox lets defaultTracer store a Tracer in an atomic.Value, which
+// requires every value it holds to share one concrete type -- Tracer itself
+// doesn't, since different callers install different implementations.
+type tracerBox struct {
+	t Tracer
+}
+
+// defaultTracer is used by any parse whose State doesn't set its own Tracer.
+// It's stored in an atomic.Value rather than a plain var since SetTracer can
+// be called concurrently with parses already in flight, e.g. to turn tracing
+// on for a single request in a server without recompiling.
+var defaultTracer atomic.Value
+
+// SetTracer installs t as the tracer used by every parse that doesn't set
+// its own State.Tracer. Pass nil to disable tracing again.
+func SetTracer(t Tracer) {
+	defaultTracer.Store(tracerBox{t})
+}
+
+func (ps *State) tracer() Tracer {
+	if ps.Tracer != nil {
+		return ps.Tracer
+	}
+	if box, ok := defaultTracer.Load().(tracerBox); ok {
+		return box.t
+	}
+	return nil
+}
+
+// Trace wraps p so that its attempts are reported to whichever Tracer is
+// active: ps.Tracer if the parse set one, otherwise the tracer installed
+// with SetTracer. With no tracer active, Trace just runs p.
+func Trace(name string, p interface{}) Parser {
+	parser := parserify(p)
+
+	return func(ps *State, node *Node) {
+		t := ps.tracer()
+		if t == nil {
+			parser(ps, node)
+			return
+		}
+
+		t.Enter(name, ps)
+		parser(ps, node)
+		if ps.Errored() {
+			t.Exit(name, ps, nil, &ps.Error)
+		} else {
+			t.Exit(name, ps, node, nil)
+		}
+	}
+}
+
+// textTracer is the Tracer returned by TextTracer.
+type textTracer struct {
+	w     io.Writer
+	depth int
+}
+
+// TextTracer returns a Tracer that writes a human-readable, indented trace
+// to w: on entry the parser's name and a preview of the remaining input, on
+// exit whether it matched and what.
+func TextTracer(w io.Writer) Tracer {
+	return &textTracer{w: w}
+}
+
+func (t *textTracer) Enter(name string, ps *State) {
+	fmt.Fprintf(t.w, "%s%s? %q\n", strings.Repeat("  ", t.depth), name, ps.Preview(15))
+	t.depth++
+}
+
+func (t *textTracer) Exit(name string, ps *State, result *Node, err *Error) {
+	t.depth--
+	indent := strings.Repeat("  ", t.depth)
+	if err != nil {
+		fmt.Fprintf(t.w, "%s%s: no match (%s)\n", indent, name, err.Error())
+		return
+	}
+	fmt.Fprintf(t.w, "%s%s: matched %q\n", indent, name, result.Token)
+}
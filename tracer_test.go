@@ -0,0 +1,117 @@
+package goparsify
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type recordedCall struct {
+	event string
+	name  string
+	ok    bool
+}
+
+type recordingTracer struct {
+	calls []recordedCall
+}
+
+func (r *recordingTracer) Enter(name string, ps *State) {
+	r.calls = append(r.calls, recordedCall{event: "enter", name: name})
+}
+
+func (r *recordingTracer) Exit(name string, ps *State, result *Node, err *Error) {
+	r.calls = append(r.calls, recordedCall{event: "exit", name: name, ok: err == nil})
+}
+
+// countingTracer only increments a counter, so that it's safe for several
+// goroutines to share a single instance -- unlike recordingTracer, whose
+// slice isn't safe for concurrent Enter/Exit calls.
+type countingTracer struct {
+	enters int64
+}
+
+func (c *countingTracer) Enter(name string, ps *State) {
+	atomic.AddInt64(&c.enters, 1)
+}
+
+func (c *countingTracer) Exit(name string, ps *State, result *Node, err *Error) {}
+
+func TestTrace(t *testing.T) {
+	t.Run("passes through parsing unchanged with no tracer active", func(t *testing.T) {
+		node, ps := runParser("hello world", Trace("greeting", Exact("hello")))
+		require.Equal(t, "hello", node.Token)
+		require.False(t, ps.Errored())
+	})
+
+	t.Run("reports Enter/Exit to a per-State tracer", func(t *testing.T) {
+		rec := &recordingTracer{}
+		ps := NewState("hello world")
+		ps.Tracer = rec
+
+		var node Node
+		Trace("greeting", Exact("hello"))(ps, &node)
+
+		require.Equal(t, []recordedCall{
+			{event: "enter", name: "greeting"},
+			{event: "exit", name: "greeting", ok: true},
+		}, rec.calls)
+	})
+
+	t.Run("reports failure", func(t *testing.T) {
+		rec := &recordingTracer{}
+		ps := NewState("goodbye")
+		ps.Tracer = rec
+
+		var node Node
+		Trace("greeting", Exact("hello"))(ps, &node)
+
+		require.Len(t, rec.calls, 2)
+		require.False(t, rec.calls[1].ok)
+	})
+
+	t.Run("falls back to the tracer installed with SetTracer", func(t *testing.T) {
+		rec := &recordingTracer{}
+		SetTracer(rec)
+		defer SetTracer(nil)
+
+		var node Node
+		Trace("greeting", Exact("hello"))(NewState("hello"), &node)
+
+		require.Len(t, rec.calls, 2)
+	})
+
+	t.Run("survives concurrent SetTracer calls and parses", func(t *testing.T) {
+		defer SetTracer(nil)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				SetTracer(&countingTracer{})
+			}()
+			go func() {
+				defer wg.Done()
+				var node Node
+				Trace("greeting", Exact("hello"))(NewState("hello"), &node)
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func TestTextTracer(t *testing.T) {
+	var buf bytes.Buffer
+	ps := NewState("hello world")
+	ps.Tracer = TextTracer(&buf)
+
+	var node Node
+	Trace("greeting", Exact("hello"))(ps, &node)
+
+	require.Contains(t, buf.String(), `greeting? "hello world"`)
+	require.Contains(t, buf.String(), `greeting: matched "hello"`)
+}